@@ -2,29 +2,31 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
-	"net/http"
 	"os"
-	"os/signal"
-	"sync"
-	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/qdm12/ddns-updater/internal/backup"
+	"github.com/qdm12/ddns-updater/internal/configapi"
 	"github.com/qdm12/ddns-updater/internal/data"
 	"github.com/qdm12/ddns-updater/internal/health"
+	"github.com/qdm12/ddns-updater/internal/logging"
+	"github.com/qdm12/ddns-updater/internal/metrics"
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/notify"
 	"github.com/qdm12/ddns-updater/internal/params"
 	"github.com/qdm12/ddns-updater/internal/persistence"
 	recordslib "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/rpc"
 	"github.com/qdm12/ddns-updater/internal/server"
 	"github.com/qdm12/ddns-updater/internal/splash"
 	"github.com/qdm12/ddns-updater/internal/update"
-	"github.com/qdm12/golibs/admin"
-	"github.com/qdm12/golibs/logging"
 	"github.com/qdm12/golibs/network"
 	"github.com/qdm12/golibs/network/connectivity"
+	"golang.org/x/sync/errgroup"
 )
 
 func main() {
@@ -33,17 +35,24 @@ func main() {
 	// returns 2 on os signal
 }
 
+const shutdownNotifyTimeout = 5 * time.Second
+
 type allParams struct {
-	period          time.Duration
-	ipMethod        models.IPMethod
-	ipv4Method      models.IPMethod
-	ipv6Method      models.IPMethod
-	dir             string
-	dataDir         string
-	listeningPort   uint16
-	rootURL         string
-	backupPeriod    time.Duration
-	backupDirectory string
+	period           time.Duration
+	ipMethod         models.IPMethod
+	ipv4Method       models.IPMethod
+	ipv6Method       models.IPMethod
+	dir              string
+	dataDir          string
+	listeningPort    uint16
+	rootURL          string
+	backupPeriod     time.Duration
+	backupDirectory  string
+	configAPIAddress string
+	configAPIToken   string
+	metricsAddress   string
+	rpcAddress       string
+	rpcToken         string
 }
 
 func _main(ctx context.Context, timeNow func() time.Time) int {
@@ -70,7 +79,7 @@ func _main(ctx context.Context, timeNow func() time.Time) int {
 		paramsReader.GetVcsRef(),
 		paramsReader.GetBuildDate()))
 
-	notify, err := setupGotify(paramsReader, logger)
+	notifier, err := setupNotifiers(paramsReader, logger)
 	if err != nil {
 		logger.Error(err)
 		return 1
@@ -79,24 +88,25 @@ func _main(ctx context.Context, timeNow func() time.Time) int {
 	p, err := getParams(paramsReader, logger)
 	if err != nil {
 		logger.Error(err)
-		notify(4, err) //nolint:gomnd
+		_ = notifier.Notify(ctx, notify.LevelError, err.Error())
 		return 1
 	}
 
 	persistentDB, err := persistence.NewJSON(p.dataDir)
 	if err != nil {
 		logger.Error(err)
-		notify(4, err) //nolint:gomnd
+		_ = notifier.Notify(ctx, notify.LevelError, err.Error())
 		return 1
 	}
-	settings, warnings, err := paramsReader.GetSettings(p.dataDir + "/config.json")
+	configPath := p.dataDir + "/config.json"
+	settings, warnings, err := paramsReader.GetSettings(configPath)
 	for _, w := range warnings {
 		logger.Warn(w)
-		notify(2, w) //nolint:gomnd
+		_ = notifier.Notify(ctx, notify.LevelWarn, w)
 	}
 	if err != nil {
 		logger.Error(err)
-		notify(4, err) //nolint:gomnd
+		_ = notifier.Notify(ctx, notify.LevelError, err.Error())
 		return 1
 	}
 	if len(settings) > 1 {
@@ -115,7 +125,7 @@ func _main(ctx context.Context, timeNow func() time.Time) int {
 		events, err := persistentDB.GetEvents(s.Domain(), s.Host())
 		if err != nil {
 			logger.Error(err)
-			notify(4, err) //nolint:gomnd
+			_ = notifier.Notify(ctx, notify.LevelError, err.Error())
 			return 1
 		}
 		records[i] = recordslib.New(s, events)
@@ -123,63 +133,106 @@ func _main(ctx context.Context, timeNow func() time.Time) int {
 	HTTPTimeout, err := paramsReader.GetHTTPTimeout()
 	if err != nil {
 		logger.Error(err)
-		notify(4, err) //nolint:gomnd
+		_ = notifier.Notify(ctx, notify.LevelError, err.Error())
 		return 1
 	}
 	client := network.NewClient(HTTPTimeout)
-	defer client.Close()
 	db := data.NewDatabase(records, persistentDB)
-	defer func() {
-		if err := db.Close(); err != nil {
-			logger.Error(err)
-		}
-	}()
 
-	wg := &sync.WaitGroup{}
-	defer wg.Wait()
+	registry := prometheus.NewRegistry()
+	recorder := metrics.NewPrometheus(registry)
+	metricsServer := metrics.NewServer(p.metricsAddress, registry)
 
-	updater := update.NewUpdater(db, client, notify)
-	ipGetter := update.NewIPGetter(client, p.ipMethod, p.ipv4Method, p.ipv6Method)
+	updater := update.NewUpdater(db, client, notifier, recorder, timeNow)
+	ipGetter := update.NewIPGetter(client, p.ipMethod, p.ipv4Method, p.ipv6Method, recorder)
 	runner := update.NewRunner(db, updater, ipGetter, logger, timeNow)
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
-	forceUpdate := make(chan struct{})
-	go runner.Run(ctx, p.period, forceUpdate)
-	forceUpdate <- struct{}{}
+	forceUpdate := make(chan update.ForceUpdateRequest)
 
 	const healthServerAddr = "127.0.0.1:9999"
 	isHealthy := health.MakeIsHealthy(db, net.LookupIP, logger)
 	healthServer := health.NewServer(healthServerAddr,
-		logger.WithPrefix("healthcheck server: "),
+		logger.WithPrefix("healthcheck server: ").With(logging.String("component", "health")),
 		isHealthy)
-	wg.Add(1)
-	go healthServer.Run(ctx, wg)
 
 	address := fmt.Sprintf("0.0.0.0:%d", p.listeningPort)
 	uiDir := p.dir + "/ui"
-	server := server.New(address, p.rootURL, uiDir, db, logger.WithPrefix("http server: "), forceUpdate)
-	wg.Add(1)
-	go server.Run(ctx, wg)
-	notify(1, fmt.Sprintf("Launched with %d records to watch", len(records)))
-
-	go backupRunLoop(ctx, p.backupPeriod, p.dir, p.backupDirectory, logger, timeNow)
-
-	osSignals := make(chan os.Signal, 1)
-	signal.Notify(osSignals,
-		syscall.SIGINT,
-		syscall.SIGTERM,
-		os.Interrupt,
-	)
-	select {
-	case signal := <-osSignals:
-		message := fmt.Sprintf("Stopping program: caught OS signal %q", signal)
+	httpServer := server.New(address, p.rootURL, uiDir, db,
+		logger.WithPrefix("http server: ").With(logging.String("component", "http")), forceUpdate)
+
+	reloadLogger := logger.WithPrefix("config reload: ")
+	doReload := func() error {
+		return reloadConfig(ctx, paramsReader, reloadLogger, notifier, persistentDB, db, runner, configPath)
+	}
+	configAPI := configapi.New(p.configAPIAddress, p.configAPIToken, configPath, paramsReader,
+		logger.WithPrefix("config api: ").With(logging.String("component", "configapi")), doReload)
+
+	backupOnce := func() (string, error) {
+		return runBackup(p.dir, p.backupDirectory, timeNow(), recorder,
+			logger.WithPrefix("backup: ").With(logging.String("component", "backup")))
+	}
+	rpcServer := rpc.New(p.rpcAddress, p.rpcToken,
+		logger.WithPrefix("rpc: ").With(logging.String("component", "rpc")),
+		rpc.Handlers(rpc.Dependencies{
+			DB:          db,
+			GetEvents:   persistentDB.GetEvents,
+			ForceUpdate: forceUpdate,
+			GetIP:       ipGetter.IP,
+			Reload:      doReload,
+			BackupRun:   backupOnce,
+		}))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	g, ctx := errgroup.WithContext(ctx)
+
+	reload := make(chan struct{})
+	g.Go(signalerRun(ctx, cancel, reload))
+	g.Go(func() error { return runner.Run(ctx, p.period, forceUpdate) })
+	g.Go(func() error { return healthServer.Run(ctx) })
+	g.Go(func() error { return httpServer.Run(ctx) })
+	g.Go(func() error { return configAPI.Run(ctx) })
+	g.Go(func() error { return metricsServer.Run(ctx) })
+	g.Go(func() error { return rpcServer.Run(ctx) })
+	g.Go(func() error {
+		return backupRunLoop(ctx, p.backupPeriod, p.dir, p.backupDirectory, logger, timeNow, recorder)
+	})
+	g.Go(func() error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-reload:
+				if err := doReload(); err != nil {
+					reloadLogger.Error(err)
+					_ = notifier.Notify(ctx, notify.LevelWarn, err.Error())
+				}
+			}
+		}
+	})
+	forceUpdate <- update.ForceUpdateRequest{}
+	_ = notifier.Notify(ctx, notify.LevelInfo, fmt.Sprintf("Launched with %d records to watch", len(records)))
+
+	err = g.Wait()
+
+	if closeErr := db.Close(); closeErr != nil {
+		logger.Error(closeErr)
+	}
+	client.Close()
+
+	switch {
+	case errors.Is(err, errSignalShutdown):
+		message := fmt.Sprintf("Stopping program: %s", err)
 		logger.Warn(message)
-		notify(2, message) //nolint:gomnd
-		return 2           //nolint:gomnd
-	case <-ctx.Done():
-		message := fmt.Sprintf("Stopping program: %s", ctx.Err())
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownNotifyTimeout)
+		_ = notifier.Notify(shutdownCtx, notify.LevelWarn, message)
+		shutdownCancel()
+		return 2 //nolint:gomnd
+	case err != nil:
+		message := fmt.Sprintf("Stopping program: %s", err)
 		logger.Warn(message)
 		return 1
+	default:
+		return 0
 	}
 }
 
@@ -189,27 +242,38 @@ func setupLogger() (logging.Logger, error) {
 	if err != nil {
 		return nil, err
 	}
-	return logging.NewLogger(encoding, level)
+	return logging.New(encoding, level)
 }
 
-func setupGotify(paramsReader params.Reader, logger logging.Logger) (
-	notify func(priority int, messageArgs ...interface{}), err error) {
+// setupNotifiers builds a notify.Notifier fanning out to every configured
+// backend: the legacy GOTIFY_URL/GOTIFY_TOKEN env vars if set, plus whatever
+// NOTIFY_URLS lists (gotify://, webhook://, discord://, slack://,
+// telegram://, smtp://).
+func setupNotifiers(paramsReader params.Reader, logger logging.Logger) (notify.Notifier, error) {
+	var notifiers []notify.Notifier
+
 	gotifyURL, err := paramsReader.GetGotifyURL()
 	if err != nil {
 		return nil, err
-	} else if gotifyURL == nil {
-		return func(priority int, messageArgs ...interface{}) {}, nil
 	}
-	gotifyToken, err := paramsReader.GetGotifyToken()
-	if err != nil {
-		return nil, err
+	if gotifyURL != nil {
+		gotifyToken, err := paramsReader.GetGotifyToken()
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, notify.NewGotify(*gotifyURL, gotifyToken))
 	}
-	gotify := admin.NewGotify(*gotifyURL, gotifyToken, &http.Client{Timeout: time.Second})
-	return func(priority int, messageArgs ...interface{}) {
-		if err := gotify.Notify("DDNS Updater", priority, messageArgs...); err != nil {
-			logger.Error(err)
+
+	if rawURLs := os.Getenv("NOTIFY_URLS"); rawURLs != "" {
+		urlNotifiers, err := notify.ParseURLs(rawURLs)
+		if err != nil {
+			return nil, err
 		}
-	}, nil
+		notifiers = append(notifiers, urlNotifiers...)
+	}
+
+	logger.Info("notifying through %d backend(s)", len(notifiers))
+	return notify.NewMulti(notifiers...), nil
 }
 
 func getParams(paramsReader params.Reader, logger logging.Logger) (p allParams, err error) {
@@ -257,33 +321,110 @@ func getParams(paramsReader params.Reader, logger logging.Logger) (p allParams,
 	if err != nil {
 		return p, err
 	}
+	const defaultConfigAPIAddress = "0.0.0.0:8001"
+	p.configAPIAddress = defaultConfigAPIAddress
+	if address := os.Getenv("CONFIG_API_ADDRESS"); address != "" {
+		p.configAPIAddress = address
+	}
+	p.configAPIToken = os.Getenv("CONFIG_API_TOKEN")
+	const defaultMetricsAddress = "0.0.0.0:8002"
+	p.metricsAddress = defaultMetricsAddress
+	if address := os.Getenv("METRICS_ADDRESS"); address != "" {
+		p.metricsAddress = address
+	}
+	const defaultRPCAddress = "0.0.0.0:8003"
+	p.rpcAddress = defaultRPCAddress
+	if address := os.Getenv("RPC_ADDRESS"); address != "" {
+		p.rpcAddress = address
+	}
+	p.rpcToken = os.Getenv("RPC_TOKEN")
 	return p, nil
 }
 
+// reloadConfig re-reads configPath, reconciles the records database against
+// the new settings (closing removed records, seeding new ones with empty
+// history) and pushes the current period into runner. It validates the full
+// config through paramsReader before anything currently running is touched,
+// so a bad reload leaves the running state untouched.
+//
+// Only the records settings in configPath are reloadable this way. The
+// period is re-read from the PERIOD env var, whose value does not change for
+// the life of the process; it, the IP methods, the notify backends and the
+// backup schedule all require a restart to change.
+func reloadConfig(ctx context.Context, paramsReader params.Reader, logger logging.Logger, notifier notify.Notifier,
+	persistentDB persistence.Database, db *data.Database, runner *update.Runner, configPath string) error {
+	settings, warnings, err := paramsReader.GetSettings(configPath)
+	for _, w := range warnings {
+		logger.Warn(w)
+		_ = notifier.Notify(ctx, notify.LevelWarn, w)
+	}
+	if err != nil {
+		return err
+	}
+
+	period, warnings, err := paramsReader.GetPeriod()
+	for _, w := range warnings {
+		logger.Warn(w)
+	}
+	if err != nil {
+		return err
+	}
+
+	records := make([]recordslib.Record, len(settings))
+	for i, s := range settings {
+		events, err := persistentDB.GetEvents(s.Domain(), s.Host())
+		if err != nil {
+			return err
+		}
+		records[i] = recordslib.New(s, events)
+	}
+
+	if err := db.SetRecords(records); err != nil {
+		return err
+	}
+	runner.ReloadConfig(period, records)
+	logger.Info("reloaded configuration: %d records, period %s", len(records), period)
+	return nil
+}
+
 func backupRunLoop(ctx context.Context, backupPeriod time.Duration, exeDir, outputDir string,
-	logger logging.Logger, timeNow func() time.Time) {
+	logger logging.Logger, timeNow func() time.Time, recorder metrics.Recorder) error {
 	logger = logger.WithPrefix("backup: ")
 	if backupPeriod == 0 {
 		logger.Info("disabled")
-		return
+		<-ctx.Done()
+		return nil
 	}
 	logger.Info("each %s; writing zip files to directory %s", backupPeriod, outputDir)
-	ziper := backup.NewZiper()
 	timer := time.NewTimer(backupPeriod)
 	for {
-		filepath := fmt.Sprintf("%s/ddns-updater-backup-%d.zip", outputDir, timeNow().UnixNano())
-		if err := ziper.ZipFiles(
-			filepath,
-			fmt.Sprintf("%s/data/updates.json", exeDir),
-			fmt.Sprintf("%s/data/config.json", exeDir)); err != nil {
-			logger.Error(err)
+		if _, err := runBackup(exeDir, outputDir, timeNow(), recorder, logger); err != nil {
+			logger.Errorw("backup failed", logging.Err(err))
 		}
 		select {
 		case <-timer.C:
 			timer.Reset(backupPeriod)
 		case <-ctx.Done():
 			timer.Stop()
-			return
+			return nil
 		}
 	}
 }
+
+// runBackup zips the current updates and config files into outputDir and
+// returns the path written. It is shared by the periodic backupRunLoop and
+// the on-demand rpc backup.run method.
+func runBackup(exeDir, outputDir string, now time.Time, recorder metrics.Recorder, logger logging.Logger) (string, error) {
+	filepath := fmt.Sprintf("%s/ddns-updater-backup-%d.zip", outputDir, now.UnixNano())
+	backupLogger := logger.With(logging.String("filepath", filepath))
+	ziper := backup.NewZiper()
+	if err := ziper.ZipFiles(
+		filepath,
+		fmt.Sprintf("%s/data/updates.json", exeDir),
+		fmt.Sprintf("%s/data/config.json", exeDir)); err != nil {
+		return "", err
+	}
+	backupLogger.Infow("backup written")
+	recorder.SetBackupLastSuccess(now)
+	return filepath, nil
+}
@@ -0,0 +1,33 @@
+//go:build !unix
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// errSignalShutdown is returned by signalerRun when the process was asked to
+// stop through SIGINT/SIGTERM, as opposed to some other goroutine failing.
+var errSignalShutdown = errors.New("received termination signal")
+
+// signalerRun returns a function suitable for errgroup.Group.Go: it listens
+// for OS signals and cancels ctx to trigger a shutdown. SIGHUP reload is a
+// Unix-only concept so the reload channel is never written to here.
+func signalerRun(ctx context.Context, cancel context.CancelFunc, _ chan<- struct{}) func() error {
+	return func() error {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(signals)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-signals:
+			cancel()
+			return errSignalShutdown
+		}
+	}
+}
@@ -0,0 +1,44 @@
+//go:build unix
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// errSignalShutdown is returned by signalerRun when the process was asked to
+// stop through SIGINT/SIGTERM, as opposed to some other goroutine failing.
+var errSignalShutdown = errors.New("received termination signal")
+
+// signalerRun returns a function suitable for errgroup.Group.Go: it listens
+// for OS signals and either requests a config reload (SIGHUP) or cancels ctx
+// to trigger a shutdown (SIGINT/SIGTERM).
+func signalerRun(ctx context.Context, cancel context.CancelFunc, reload chan<- struct{}) func() error {
+	return func() error {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case sig := <-signals:
+				switch sig {
+				case syscall.SIGHUP:
+					select {
+					case reload <- struct{}{}:
+					case <-ctx.Done():
+						return nil
+					}
+				default:
+					cancel()
+					return errSignalShutdown
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+//go:build unix
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+const signalerTestTimeout = 2 * time.Second
+
+func TestSignalerRunReload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reload := make(chan struct{}, 1)
+
+	done := make(chan error, 1)
+	go func() { done <- signalerRun(ctx, cancel, reload)() }()
+
+	// Give signal.Notify a moment to register before raising SIGHUP.
+	time.Sleep(10 * time.Millisecond)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("raising SIGHUP: %v", err)
+	}
+
+	select {
+	case <-reload:
+	case <-time.After(signalerTestTimeout):
+		t.Fatal("SIGHUP did not trigger a reload request")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("signalerRun returned %v after ctx cancellation, want nil", err)
+		}
+	case <-time.After(signalerTestTimeout):
+		t.Fatal("signalerRun did not return after ctx cancellation")
+	}
+}
+
+func TestSignalerRunShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reload := make(chan struct{}, 1)
+
+	done := make(chan error, 1)
+	go func() { done <- signalerRun(ctx, cancel, reload)() }()
+
+	time.Sleep(10 * time.Millisecond)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatalf("raising SIGTERM: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, errSignalShutdown) {
+			t.Errorf("err = %v, want errSignalShutdown", err)
+		}
+	case <-time.After(signalerTestTimeout):
+		t.Fatal("signalerRun did not return after SIGTERM")
+	}
+
+	if ctx.Err() == nil {
+		t.Error("ctx was not cancelled by SIGTERM")
+	}
+}
@@ -0,0 +1,53 @@
+// Package backup zips up the updater's data files for safekeeping.
+package backup
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Ziper writes a set of source files into a single zip archive.
+type Ziper struct{}
+
+// NewZiper creates a Ziper.
+func NewZiper() *Ziper { return &Ziper{} }
+
+// ZipFiles writes srcPaths into a new zip archive at destPath, each keyed by
+// its base name. Missing source files are skipped.
+func (z *Ziper) ZipFiles(destPath string, srcPaths ...string) error {
+	destFile, err := os.Create(destPath)
+	if err != nil {
+		return fmt.Errorf("creating backup archive: %w", err)
+	}
+	defer destFile.Close()
+
+	zipWriter := zip.NewWriter(destFile)
+	defer zipWriter.Close()
+
+	for _, srcPath := range srcPaths {
+		if err := addFile(zipWriter, srcPath); err != nil {
+			return fmt.Errorf("adding %s to backup archive: %w", srcPath, err)
+		}
+	}
+	return nil
+}
+
+func addFile(zipWriter *zip.Writer, srcPath string) error {
+	srcFile, err := os.Open(srcPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	entryWriter, err := zipWriter.Create(filepath.Base(srcPath))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entryWriter, srcFile)
+	return err
+}
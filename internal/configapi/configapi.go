@@ -0,0 +1,143 @@
+// Package configapi exposes config.json's DNS record settings over HTTP so
+// they can be inspected and edited without restarting the container. Other
+// runtime settings (IP methods, period, notify backends, backup schedule)
+// are still env-var only and are not affected by this API.
+package configapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/qdm12/ddns-updater/internal/logging"
+	"github.com/qdm12/ddns-updater/internal/params"
+)
+
+// Server serves GET/PUT /api/v1/config, validating any written config
+// through paramsReader before it replaces the file on disk.
+type Server struct {
+	address      string
+	token        string
+	configPath   string
+	paramsReader params.Reader
+	logger       logging.Logger
+	onReload     func() error
+}
+
+// New creates a config API server. If token is empty, requests are not
+// authenticated; otherwise callers must send `Authorization: Bearer <token>`.
+// onReload is invoked after a PUT successfully validates and writes the new
+// config, so callers can pick up the change without a restart.
+func New(address, token, configPath string, paramsReader params.Reader,
+	logger logging.Logger, onReload func() error) *Server {
+	return &Server{
+		address:      address,
+		token:        token,
+		configPath:   configPath,
+		paramsReader: paramsReader,
+		logger:       logger,
+		onReload:     onReload,
+	}
+}
+
+// Run serves the config API until ctx is cancelled.
+func (s *Server) Run(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/config", s.handleConfig)
+	httpServer := &http.Server{Addr: s.address, Handler: s.authenticate(mux)}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpServer.ListenAndServe() }()
+
+	select {
+	case <-ctx.Done():
+		return httpServer.Shutdown(context.Background())
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+s.token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.getConfig(w, r)
+	case http.MethodPut:
+		s.putConfig(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) getConfig(w http.ResponseWriter, _ *http.Request) {
+	data, err := os.ReadFile(s.configPath)
+	if err != nil {
+		s.logger.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// putConfig writes the request body to a staging file and only swaps it into
+// configPath once paramsReader accepts it as valid settings, so a bad PUT
+// leaves the running configuration untouched.
+func (s *Server) putConfig(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stagingPath := s.configPath + ".staging"
+	const stagingPerms = 0o600
+	if err := os.WriteFile(stagingPath, body, stagingPerms); err != nil {
+		s.logger.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(stagingPath)
+
+	_, warnings, err := s.paramsReader.GetSettings(stagingPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, warning := range warnings {
+		s.logger.Warn(warning)
+	}
+
+	if err := os.Rename(stagingPath, s.configPath); err != nil {
+		s.logger.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if s.onReload != nil {
+		if err := s.onReload(); err != nil {
+			s.logger.Error(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
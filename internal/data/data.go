@@ -0,0 +1,78 @@
+// Package data holds the in-memory view of all configured records, backed
+// by persistence.Database for their update history.
+package data
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/qdm12/ddns-updater/internal/persistence"
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// Database is the live set of records the updater is watching.
+type Database struct {
+	mutex        sync.RWMutex
+	records      []records.Record
+	persistentDB persistence.Database
+}
+
+// NewDatabase builds a Database seeded with records, backed by persistentDB.
+func NewDatabase(records []records.Record, persistentDB persistence.Database) *Database {
+	return &Database{records: records, persistentDB: persistentDB}
+}
+
+// Records returns a snapshot of the currently configured records.
+func (db *Database) Records() []records.Record {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+	recordsCopy := make([]records.Record, len(db.records))
+	copy(recordsCopy, db.records)
+	return recordsCopy
+}
+
+// SetRecords reconciles the configured records against newRecords: records
+// no longer present have their stored history removed, and the rest are
+// swapped in wholesale (new records arrive already seeded with their
+// existing history, read by the caller before calling SetRecords).
+func (db *Database) SetRecords(newRecords []records.Record) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	stillPresent := make(map[key]bool, len(newRecords))
+	for _, record := range newRecords {
+		stillPresent[key{record.Domain(), record.Host()}] = true
+	}
+	for _, record := range db.records {
+		k := key{record.Domain(), record.Host()}
+		if stillPresent[k] {
+			continue
+		}
+		if err := db.persistentDB.RemoveRecord(k.domain, k.host); err != nil {
+			return fmt.Errorf("removing stale record %s %s: %w", k.domain, k.host, err)
+		}
+	}
+
+	db.records = newRecords
+	return nil
+}
+
+type key struct{ domain, host string }
+
+// StoreEvent appends event to domain/host's history, both in the persistent
+// database and on the matching in-memory record, if any.
+func (db *Database) StoreEvent(domain, host string, event records.Event) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	for _, record := range db.records {
+		if record.Domain() == domain && record.Host() == host {
+			record.AddEvent(event)
+			break
+		}
+	}
+	return db.persistentDB.StoreEvent(domain, host, event)
+}
+
+func (db *Database) Close() error {
+	return db.persistentDB.Close()
+}
@@ -0,0 +1,73 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// fakePersistentDB is a persistence.Database test double that records which
+// domain/host pairs RemoveRecord was called with.
+type fakePersistentDB struct {
+	removed []key
+}
+
+func (f *fakePersistentDB) GetEvents(string, string) ([]records.Event, error) { return nil, nil }
+func (f *fakePersistentDB) StoreEvent(string, string, records.Event) error    { return nil }
+func (f *fakePersistentDB) Close() error                                      { return nil }
+
+func (f *fakePersistentDB) RemoveRecord(domain, host string) error {
+	f.removed = append(f.removed, key{domain, host})
+	return nil
+}
+
+func TestDatabaseSetRecordsReconciles(t *testing.T) {
+	kept := records.New(records.NewCustom("kept.example.com", "@", ""), nil)
+	dropped := records.New(records.NewCustom("dropped.example.com", "@", ""), nil)
+	added := records.New(records.NewCustom("added.example.com", "@", ""), nil)
+
+	persistentDB := &fakePersistentDB{}
+	db := NewDatabase([]records.Record{kept, dropped}, persistentDB)
+
+	if err := db.SetRecords([]records.Record{kept, added}); err != nil {
+		t.Fatalf("SetRecords: %v", err)
+	}
+
+	if want := []key{{"dropped.example.com", "@"}}; !keysEqual(persistentDB.removed, want) {
+		t.Errorf("removed = %v, want %v", persistentDB.removed, want)
+	}
+
+	got := db.Records()
+	if len(got) != 2 {
+		t.Fatalf("Records() = %d entries, want 2", len(got))
+	}
+	domains := map[string]bool{got[0].Domain(): true, got[1].Domain(): true}
+	if !domains["kept.example.com"] || !domains["added.example.com"] {
+		t.Errorf("Records() = %v, want kept and added", domains)
+	}
+}
+
+func TestDatabaseSetRecordsNoneDropped(t *testing.T) {
+	kept := records.New(records.NewCustom("kept.example.com", "@", ""), nil)
+	persistentDB := &fakePersistentDB{}
+	db := NewDatabase([]records.Record{kept}, persistentDB)
+
+	if err := db.SetRecords([]records.Record{kept}); err != nil {
+		t.Fatalf("SetRecords: %v", err)
+	}
+	if len(persistentDB.removed) != 0 {
+		t.Errorf("removed = %v, want none", persistentDB.removed)
+	}
+}
+
+func keysEqual(got []key, want []key) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
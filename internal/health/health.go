@@ -0,0 +1,127 @@
+// Package health implements the Docker HEALTHCHECK contract: a long-running
+// HTTP server reporting whether every record is up to date, queried by a
+// short-lived client invocation of the same binary.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/qdm12/ddns-updater/internal/data"
+	"github.com/qdm12/ddns-updater/internal/logging"
+)
+
+const clientModeArg = "healthcheck"
+
+// IsClientMode reports whether the process was launched to perform a
+// healthcheck query rather than to run the long-running updater.
+func IsClientMode(args []string) bool {
+	return len(args) > 1 && args[1] == clientModeArg
+}
+
+const healthServerAddr = "127.0.0.1:9999"
+
+// Client queries a running instance's health server.
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient builds a Client.
+func NewClient() *Client {
+	return &Client{httpClient: &http.Client{}}
+}
+
+// Query reports an error if the running instance is unhealthy.
+func (c *Client) Query(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+healthServerAddr, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: HTTP status %s", errUnhealthy, resp.Status)
+	}
+	return nil
+}
+
+var errUnhealthy = errors.New("instance reported unhealthy")
+
+// IsHealthy reports nil if every record resolves to its expected IP.
+type IsHealthy func() error
+
+// MakeIsHealthy builds an IsHealthy that compares each record's last known
+// good IP against what lookupIP resolves for its domain.
+func MakeIsHealthy(db *data.Database, lookupIP func(host string) ([]net.IP, error), logger logging.Logger) IsHealthy {
+	return func() error {
+		for _, record := range db.Records() {
+			expected := record.CurrentIP()
+			if expected == nil {
+				continue
+			}
+			resolved, err := lookupIP(record.Domain())
+			if err != nil {
+				logger.Warn("healthcheck: resolving %s: %s", record.Domain(), err)
+				continue
+			}
+			if !containsIP(resolved, expected) {
+				return fmt.Errorf("%w: %s does not resolve to %s", errUnhealthy, record.Domain(), expected)
+			}
+		}
+		return nil
+	}
+}
+
+func containsIP(ips []net.IP, target net.IP) bool {
+	for _, ip := range ips {
+		if ip.Equal(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Server serves the healthcheck HTTP endpoint queried by Client.
+type Server struct {
+	address   string
+	logger    logging.Logger
+	isHealthy IsHealthy
+}
+
+// NewServer builds a health Server.
+func NewServer(address string, logger logging.Logger, isHealthy IsHealthy) *Server {
+	return &Server{address: address, logger: logger, isHealthy: isHealthy}
+}
+
+// Run serves the healthcheck endpoint until ctx is cancelled.
+func (s *Server) Run(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if err := s.isHealthy(); err != nil {
+			s.logger.Warn("unhealthy: %s", err)
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	httpServer := &http.Server{Addr: s.address, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpServer.ListenAndServe() }()
+
+	select {
+	case <-ctx.Done():
+		return httpServer.Shutdown(context.Background())
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
@@ -0,0 +1,150 @@
+// Package metrics records DDNS update outcomes and exposes them to
+// Prometheus, so stuck updates and repeated provider failures can be
+// alerted on from an existing Prometheus/Grafana stack.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Recorder is the interface update and backup call sites record outcomes
+// through, so they stay decoupled from the Prometheus client library.
+type Recorder interface {
+	RecordUpdateAttempt(provider, domain, host, result string)
+	ObserveUpdateDuration(provider, domain, host string, duration time.Duration)
+	SetCurrentIP(domain, host, family string, ip net.IP)
+	SetLastSuccess(domain, host string, when time.Time)
+	RecordIPLookupFailure(method string)
+	SetBackupLastSuccess(when time.Time)
+}
+
+const namespace = "ddns"
+
+type prometheusRecorder struct {
+	updateAttemptsTotal     *prometheus.CounterVec
+	updateDurationSeconds   *prometheus.HistogramVec
+	currentIP               *prometheus.GaugeVec
+	lastSuccessTimestamp    *prometheus.GaugeVec
+	ipLookupFailuresTotal   *prometheus.CounterVec
+	backupLastSuccessTSUnix prometheus.Gauge
+
+	// currentIPLabelsMutex guards currentIPLabels so that SetCurrentIP is
+	// safe to call from several goroutines on the same recorder.
+	currentIPLabelsMutex sync.Mutex
+	currentIPLabels      map[string][]string // "domain/host/family" -> last used IP label values, for clearing stale series
+}
+
+// NewPrometheus registers the DDNS update metrics with registerer and
+// returns a Recorder that update and backup packages can call into.
+func NewPrometheus(registerer prometheus.Registerer) Recorder {
+	factory := promauto.With(registerer)
+	return &prometheusRecorder{
+		updateAttemptsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "update_attempts_total",
+			Help:      "Number of record update attempts, by provider, domain, host and result.",
+		}, []string{"provider", "domain", "host", "result"}),
+		updateDurationSeconds: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "update_duration_seconds",
+			Help:      "Duration of record update attempts in seconds.",
+		}, []string{"provider", "domain", "host"}),
+		currentIP: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "current_ip",
+			Help:      "Set to 1 for the IP currently recorded for a domain, host and family.",
+		}, []string{"domain", "host", "family", "ip"}),
+		lastSuccessTimestamp: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_success_timestamp_seconds",
+			Help:      "Unix timestamp of the last successful update, by domain and host.",
+		}, []string{"domain", "host"}),
+		ipLookupFailuresTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "ip_lookup_failures_total",
+			Help:      "Number of IP lookup failures, by method.",
+		}, []string{"method"}),
+		backupLastSuccessTSUnix: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "backup_last_success_timestamp_seconds",
+			Help:      "Unix timestamp of the last successful backup.",
+		}),
+		currentIPLabels: make(map[string][]string),
+	}
+}
+
+func (p *prometheusRecorder) RecordUpdateAttempt(provider, domain, host, result string) {
+	p.updateAttemptsTotal.WithLabelValues(provider, domain, host, result).Inc()
+}
+
+func (p *prometheusRecorder) ObserveUpdateDuration(provider, domain, host string, duration time.Duration) {
+	p.updateDurationSeconds.WithLabelValues(provider, domain, host).Observe(duration.Seconds())
+}
+
+func (p *prometheusRecorder) SetCurrentIP(domain, host, family string, ip net.IP) {
+	key := domain + "/" + host + "/" + family
+	labels := []string{domain, host, family, ip.String()}
+
+	p.currentIPLabelsMutex.Lock()
+	previous, ok := p.currentIPLabels[key]
+	p.currentIPLabels[key] = labels
+	p.currentIPLabelsMutex.Unlock()
+
+	if ok {
+		p.currentIP.DeleteLabelValues(previous...)
+	}
+	p.currentIP.WithLabelValues(labels...).Set(1)
+}
+
+func (p *prometheusRecorder) SetLastSuccess(domain, host string, when time.Time) {
+	p.lastSuccessTimestamp.WithLabelValues(domain, host).Set(float64(when.Unix()))
+}
+
+func (p *prometheusRecorder) RecordIPLookupFailure(method string) {
+	p.ipLookupFailuresTotal.WithLabelValues(method).Inc()
+}
+
+func (p *prometheusRecorder) SetBackupLastSuccess(when time.Time) {
+	p.backupLastSuccessTSUnix.Set(float64(when.Unix()))
+}
+
+// Server serves the /metrics endpoint for a Prometheus registry.
+type Server struct {
+	address    string
+	registerer prometheus.Gatherer
+}
+
+// NewServer creates a metrics server for the given registry. gatherer is
+// typically the same prometheus.Registry passed to NewPrometheus.
+func NewServer(address string, gatherer prometheus.Gatherer) *Server {
+	return &Server{address: address, registerer: gatherer}
+}
+
+// Run serves /metrics until ctx is cancelled.
+func (s *Server) Run(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(s.registerer, promhttp.HandlerOpts{}))
+	httpServer := &http.Server{Addr: s.address, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpServer.ListenAndServe() }()
+
+	select {
+	case <-ctx.Done():
+		return httpServer.Shutdown(context.Background())
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
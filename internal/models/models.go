@@ -0,0 +1,16 @@
+// Package models holds small shared value types used across the updater's
+// internal packages.
+package models
+
+// IPMethod identifies how the public IP address for a record is obtained:
+// either a named lookup provider (e.g. "google", "opendns") or a custom
+// lookup URL.
+type IPMethod string
+
+// Built-in IP lookup methods. Any other non-empty value is treated as a
+// custom lookup URL.
+const (
+	IPMethodProvider IPMethod = "provider"
+	IPMethodGoogle   IPMethod = "google"
+	IPMethodOpenDNS  IPMethod = "opendns"
+)
@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const discordTimeout = 5 * time.Second
+
+type discordNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+type discordPayload struct {
+	Content string `json:"content"`
+}
+
+// newDiscordFromURL builds a Notifier from a URL of the form
+// discord://webhookID/webhookToken.
+func newDiscordFromURL(u *url.URL) (Notifier, error) {
+	webhookID := u.Host
+	webhookToken := strings.TrimPrefix(u.Path, "/")
+	if webhookID == "" || webhookToken == "" {
+		return nil, fmt.Errorf("%w: discord URL must be discord://webhookID/webhookToken", errInvalidURL)
+	}
+	return &discordNotifier{
+		webhookURL: fmt.Sprintf("https://discord.com/api/webhooks/%s/%s", webhookID, webhookToken),
+		client:     &http.Client{Timeout: discordTimeout},
+	}, nil
+}
+
+func (d *discordNotifier) Notify(ctx context.Context, level Level, msg string, fields ...Field) error {
+	body, err := json.Marshal(discordPayload{Content: appendFields(
+		fmt.Sprintf("[%s] %s", level, msg), fields)})
+	if err != nil {
+		return fmt.Errorf("encoding discord payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building discord request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending discord notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: discord returned status %s", errNotifyFailed, resp.Status)
+	}
+	return nil
+}
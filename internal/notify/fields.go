@@ -0,0 +1,33 @@
+package notify
+
+import "strings"
+
+// appendFields renders fields as a "key=value" suffix for backends that only
+// take a plain message string.
+func appendFields(msg string, fields []Field) string {
+	if len(fields) == 0 {
+		return msg
+	}
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, field := range fields {
+		b.WriteString(" ")
+		b.WriteString(field.Key)
+		b.WriteString("=")
+		b.WriteString(field.Value)
+	}
+	return b.String()
+}
+
+// fieldsToMap converts fields to a map for backends with structured JSON
+// payloads.
+func fieldsToMap(fields []Field) map[string]string {
+	if len(fields) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(fields))
+	for _, field := range fields {
+		m[field.Key] = field.Value
+	}
+	return m
+}
@@ -0,0 +1,54 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/qdm12/golibs/admin"
+)
+
+var errGotifyMissingToken = errors.New("gotify URL missing token")
+
+type gotifyNotifier struct {
+	client *admin.Gotify
+}
+
+// NewGotify builds a Notifier from an already-parsed Gotify base URL and
+// token, for callers (such as the legacy GOTIFY_URL/GOTIFY_TOKEN env vars)
+// that don't go through a gotify:// NOTIFY_URLS entry.
+func NewGotify(baseURL url.URL, token string) Notifier {
+	client := admin.NewGotify(baseURL, token, &http.Client{Timeout: time.Second})
+	return &gotifyNotifier{client: client}
+}
+
+// newGotifyFromURL builds a Notifier from a URL of the form
+// gotify://token@host.
+func newGotifyFromURL(u *url.URL) (Notifier, error) {
+	token := u.User.Username()
+	if token == "" {
+		return nil, errGotifyMissingToken
+	}
+	return NewGotify(url.URL{Scheme: "https", Host: u.Host}, token), nil
+}
+
+func (g *gotifyNotifier) Notify(_ context.Context, level Level, msg string, fields ...Field) error {
+	return g.client.Notify("DDNS Updater", gotifyPriority(level), appendFields(msg, fields))
+}
+
+func gotifyPriority(level Level) int {
+	switch level {
+	case LevelDebug:
+		return 0
+	case LevelInfo:
+		return 1
+	case LevelWarn:
+		return 2
+	case LevelError:
+		return 4
+	default:
+		return 1
+	}
+}
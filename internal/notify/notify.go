@@ -0,0 +1,79 @@
+// Package notify fans out notifications (record updates, reload failures,
+// startup/shutdown) to one or more configured backends: Gotify, generic
+// webhooks, Discord, Slack, Telegram and SMTP.
+package notify
+
+import (
+	"context"
+	"errors"
+)
+
+// errNotifyFailed is wrapped by backends that got an unexpected response
+// from the remote service rather than a transport-level error.
+var errNotifyFailed = errors.New("notification failed")
+
+// errInvalidURL is wrapped by backends whose NOTIFY_URLS entry is missing a
+// required part (token, channel, recipient, ...).
+var errInvalidURL = errors.New("invalid notify URL")
+
+// Level replaces the four magic Gotify priority ints previously scattered
+// through _main.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Field is a structured key/value attached to a notification, e.g. domain,
+// host, provider, old/new IP, so backends with richer formats can render it.
+type Field struct {
+	Key   string
+	Value string
+}
+
+func F(key, value string) Field { return Field{Key: key, Value: value} }
+
+// Notifier sends a single notification to a backend.
+type Notifier interface {
+	Notify(ctx context.Context, level Level, msg string, fields ...Field) error
+}
+
+// MultiNotifier fans a notification out to every configured backend,
+// continuing past individual failures and returning them all joined.
+type MultiNotifier struct {
+	notifiers []Notifier
+}
+
+// NewMulti builds a MultiNotifier. A MultiNotifier with no backends is a
+// valid, silent no-op notifier.
+func NewMulti(notifiers ...Notifier) *MultiNotifier {
+	return &MultiNotifier{notifiers: notifiers}
+}
+
+func (m *MultiNotifier) Notify(ctx context.Context, level Level, msg string, fields ...Field) error {
+	var errs []error
+	for _, notifier := range m.notifiers {
+		if err := notifier.Notify(ctx, level, msg, fields...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
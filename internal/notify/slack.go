@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const slackTimeout = 5 * time.Second
+
+type slackNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// newSlackFromURL builds a Notifier from a URL of the form
+// slack://T00000000/B00000000/XXXXXXXXXXXXXXXXXXXXXXXX, mirroring a Slack
+// incoming webhook path (https://hooks.slack.com/services/<path>).
+func newSlackFromURL(u *url.URL) (Notifier, error) {
+	path := strings.Trim(u.Host+u.Path, "/")
+	if path == "" {
+		return nil, fmt.Errorf("%w: slack URL must include the webhook path", errInvalidURL)
+	}
+	return &slackNotifier{
+		webhookURL: "https://hooks.slack.com/services/" + path,
+		client:     &http.Client{Timeout: slackTimeout},
+	}, nil
+}
+
+func (s *slackNotifier) Notify(ctx context.Context, level Level, msg string, fields ...Field) error {
+	body, err := json.Marshal(slackPayload{Text: appendFields(
+		fmt.Sprintf("[%s] %s", level, msg), fields)})
+	if err != nil {
+		return fmt.Errorf("encoding slack payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending slack notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: slack returned status %s", errNotifyFailed, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const telegramTimeout = 5 * time.Second
+
+type telegramNotifier struct {
+	token   string
+	chatIDs []string
+	client  *http.Client
+}
+
+// newTelegramFromURL builds a Notifier from a URL of the form
+// telegram://token@telegram?chats=chatID1,chatID2. Inside NOTIFY_URLS, which
+// ParseURLs splits on every comma, the chat IDs must be separated by %2C
+// instead (chats=chatID1%2CchatID2).
+func newTelegramFromURL(u *url.URL) (Notifier, error) {
+	token := u.User.Username()
+	chatIDs := splitNonEmpty(u.Query().Get("chats"))
+	if token == "" || len(chatIDs) == 0 {
+		return nil, fmt.Errorf("%w: telegram URL must be telegram://token@telegram?chats=chatID", errInvalidURL)
+	}
+	return &telegramNotifier{
+		token:   token,
+		chatIDs: chatIDs,
+		client:  &http.Client{Timeout: telegramTimeout},
+	}, nil
+}
+
+func (t *telegramNotifier) Notify(ctx context.Context, level Level, msg string, fields ...Field) error {
+	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", t.token)
+	text := appendFields(fmt.Sprintf("[%s] %s", level, msg), fields)
+
+	for _, chatID := range t.chatIDs {
+		form := url.Values{
+			"chat_id": {chatID},
+			"text":    {text},
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return fmt.Errorf("building telegram request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		resp, err := t.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("sending telegram notification to chat %s: %w", chatID, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusMultipleChoices {
+			return fmt.Errorf("%w: telegram returned status %s for chat %s", errNotifyFailed, resp.Status, chatID)
+		}
+	}
+	return nil
+}
+
+// splitNonEmpty splits raw on commas, trims whitespace and drops empty
+// elements.
+func splitNonEmpty(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var errUnknownScheme = errors.New("unknown notify URL scheme")
+
+// ParseURLs builds one Notifier per comma-separated URL in raw, dispatching
+// on URL scheme, e.g.
+//
+//	NOTIFY_URLS=gotify://token@host,discord://webhookID/webhookToken
+func ParseURLs(raw string) ([]Notifier, error) {
+	var notifiers []Notifier
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		u, err := url.Parse(part)
+		if err != nil {
+			return nil, fmt.Errorf("parsing notify URL: %w", err)
+		}
+		notifier, err := newFromURL(u)
+		if err != nil {
+			return nil, fmt.Errorf("building notifier for scheme %q: %w", u.Scheme, err)
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	return notifiers, nil
+}
+
+func newFromURL(u *url.URL) (Notifier, error) {
+	switch u.Scheme {
+	case "gotify":
+		return newGotifyFromURL(u)
+	case "webhook", "webhooks":
+		return newWebhookFromURL(u)
+	case "discord":
+		return newDiscordFromURL(u)
+	case "slack":
+		return newSlackFromURL(u)
+	case "telegram":
+		return newTelegramFromURL(u)
+	case "smtp", "smtps":
+		return newSMTPFromURL(u)
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnknownScheme, u.Scheme)
+	}
+}
@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const webhookTimeout = 5 * time.Second
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+type webhookPayload struct {
+	Level   string            `json:"level"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// newWebhookFromURL builds a Notifier from a URL of the form
+// webhook://host/path, POSTing a JSON payload to https://host/path.
+func newWebhookFromURL(u *url.URL) (Notifier, error) {
+	target := *u
+	target.Scheme = "https"
+	return &webhookNotifier{
+		url:    target.String(),
+		client: &http.Client{Timeout: webhookTimeout},
+	}, nil
+}
+
+func (w *webhookNotifier) Notify(ctx context.Context, level Level, msg string, fields ...Field) error {
+	body, err := json.Marshal(webhookPayload{
+		Level:   level.String(),
+		Message: msg,
+		Fields:  fieldsToMap(fields),
+	})
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: webhook returned status %s", errNotifyFailed, resp.Status)
+	}
+	return nil
+}
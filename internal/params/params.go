@@ -0,0 +1,214 @@
+// Package params reads and validates all the updater's runtime settings,
+// from environment variables and from the records config file.
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/logging"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// Reader reads and validates runtime settings.
+type Reader interface {
+	GetVersion() string
+	GetVcsRef() string
+	GetBuildDate() string
+	GetGotifyURL() (u *url.URL, err error)
+	GetGotifyToken() (token string, err error)
+	GetPeriod() (period time.Duration, warnings []string, err error)
+	GetIPMethod() (method models.IPMethod, err error)
+	GetIPv4Method() (method models.IPMethod, err error)
+	GetIPv6Method() (method models.IPMethod, err error)
+	GetExeDir() (dir string, err error)
+	GetDataDir(exeDir string) (dir string, err error)
+	GetListeningPort() (port uint16, warning string, err error)
+	GetRootURL() (rootURL string, err error)
+	GetBackupPeriod() (period time.Duration, err error)
+	GetBackupDirectory() (dir string, err error)
+	GetHTTPTimeout() (timeout time.Duration, err error)
+	GetLoggerConfig() (encoding, level string, err error)
+	GetSettings(configPath string) (settings []records.Settings, warnings []string, err error)
+}
+
+type reader struct {
+	logger logging.Logger
+}
+
+// NewReader builds a Reader. logger may be nil; the Reader does not log
+// through it, and returns non-fatal parsing warnings to its callers instead.
+func NewReader(logger logging.Logger) Reader {
+	return &reader{logger: logger}
+}
+
+func (r *reader) GetVersion() string   { return getEnvDefault("VERSION", "unknown") }
+func (r *reader) GetVcsRef() string    { return getEnvDefault("VCS_REF", "unknown") }
+func (r *reader) GetBuildDate() string { return getEnvDefault("BUILD_DATE", "unknown") }
+
+func (r *reader) GetGotifyURL() (u *url.URL, err error) {
+	raw := os.Getenv("GOTIFY_URL")
+	if raw == "" {
+		return nil, nil
+	}
+	u, err = url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("GOTIFY_URL: %w", err)
+	}
+	return u, nil
+}
+
+func (r *reader) GetGotifyToken() (string, error) {
+	return os.Getenv("GOTIFY_TOKEN"), nil
+}
+
+func (r *reader) GetPeriod() (period time.Duration, warnings []string, err error) {
+	const defaultPeriod = 10 * time.Minute
+	raw := os.Getenv("PERIOD")
+	if raw == "" {
+		return defaultPeriod, nil, nil
+	}
+	period, err = time.ParseDuration(raw)
+	if err != nil {
+		return 0, nil, fmt.Errorf("PERIOD: %w", err)
+	}
+	const minPeriod = time.Minute
+	if period < minPeriod {
+		warnings = append(warnings, fmt.Sprintf("PERIOD %s is below the minimum of %s, using the minimum instead", period, minPeriod))
+		period = minPeriod
+	}
+	return period, warnings, nil
+}
+
+func (r *reader) GetIPMethod() (models.IPMethod, error) {
+	return getIPMethod("IP_METHOD")
+}
+
+func (r *reader) GetIPv4Method() (models.IPMethod, error) {
+	return getIPMethod("IPV4_METHOD")
+}
+
+func (r *reader) GetIPv6Method() (models.IPMethod, error) {
+	return getIPMethod("IPV6_METHOD")
+}
+
+func getIPMethod(envVar string) (models.IPMethod, error) {
+	raw := os.Getenv(envVar)
+	if raw == "" {
+		return models.IPMethodProvider, nil
+	}
+	return models.IPMethod(raw), nil
+}
+
+func (r *reader) GetExeDir() (string, error) {
+	if dir := os.Getenv("EXE_DIR"); dir != "" {
+		return dir, nil
+	}
+	return os.Getwd()
+}
+
+func (r *reader) GetDataDir(exeDir string) (string, error) {
+	return getEnvDefault("DATA_DIR", exeDir+"/data"), nil
+}
+
+func (r *reader) GetListeningPort() (port uint16, warning string, err error) {
+	const defaultPort = 8000
+	raw := os.Getenv("LISTENING_PORT")
+	if raw == "" {
+		return defaultPort, "", nil
+	}
+	parsed, err := strconv.ParseUint(raw, 10, 16)
+	if err != nil {
+		return 0, "", fmt.Errorf("LISTENING_PORT: %w", err)
+	}
+	const privilegedPortMax = 1024
+	if parsed < privilegedPortMax {
+		warning = fmt.Sprintf("LISTENING_PORT %d is a privileged port", parsed)
+	}
+	return uint16(parsed), warning, nil
+}
+
+func (r *reader) GetRootURL() (string, error) {
+	return getEnvDefault("ROOT_URL", "/"), nil
+}
+
+func (r *reader) GetBackupPeriod() (time.Duration, error) {
+	raw := os.Getenv("BACKUP_PERIOD")
+	if raw == "" {
+		return 0, nil
+	}
+	period, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("BACKUP_PERIOD: %w", err)
+	}
+	return period, nil
+}
+
+func (r *reader) GetBackupDirectory() (string, error) {
+	return getEnvDefault("BACKUP_DIRECTORY", "/backups"), nil
+}
+
+func (r *reader) GetHTTPTimeout() (time.Duration, error) {
+	const defaultTimeout = 10 * time.Second
+	raw := os.Getenv("HTTP_TIMEOUT")
+	if raw == "" {
+		return defaultTimeout, nil
+	}
+	timeout, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("HTTP_TIMEOUT: %w", err)
+	}
+	return timeout, nil
+}
+
+func (r *reader) GetLoggerConfig() (encoding, level string, err error) {
+	return getEnvDefault("LOG_ENCODING", "console"), getEnvDefault("LOG_LEVEL", "info"), nil
+}
+
+type settingsFile struct {
+	Domain      string `json:"domain"`
+	Host        string `json:"host"`
+	URLTemplate string `json:"url_template"`
+}
+
+func (r *reader) GetSettings(configPath string) (settings []records.Settings, warnings []string, err error) {
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("reading settings file: %w", err)
+	}
+
+	var entries struct {
+		Settings []settingsFile `json:"settings"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, nil, fmt.Errorf("parsing settings file: %w", err)
+	}
+
+	settings = make([]records.Settings, 0, len(entries.Settings))
+	for _, entry := range entries.Settings {
+		if entry.Domain == "" {
+			warnings = append(warnings, "skipping a settings entry with no domain")
+			continue
+		}
+		host := entry.Host
+		if host == "" {
+			host = "@"
+		}
+		settings = append(settings, records.NewCustom(entry.Domain, host, entry.URLTemplate))
+	}
+	return settings, warnings, nil
+}
+
+func getEnvDefault(envVar, fallback string) string {
+	if value := os.Getenv(envVar); value != "" {
+		return value
+	}
+	return fallback
+}
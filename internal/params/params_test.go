@@ -0,0 +1,74 @@
+package params
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	return path
+}
+
+func TestGetSettingsParsesRecords(t *testing.T) {
+	r := &reader{}
+	path := writeConfig(t, t.TempDir(), `{"settings":[
+		{"domain":"a.example.com","host":"@","url_template":"https://example.com/{ip}"},
+		{"domain":"b.example.com","host":"www","url_template":"https://example.com/{ip}"}
+	]}`)
+
+	settings, warnings, err := r.GetSettings(path)
+	if err != nil {
+		t.Fatalf("GetSettings: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("warnings = %v, want none", warnings)
+	}
+	if len(settings) != 2 {
+		t.Fatalf("got %d settings, want 2", len(settings))
+	}
+	if settings[0].Domain() != "a.example.com" || settings[0].Host() != "@" {
+		t.Errorf("settings[0] = %+v", settings[0])
+	}
+	if settings[1].Domain() != "b.example.com" || settings[1].Host() != "www" {
+		t.Errorf("settings[1] = %+v", settings[1])
+	}
+}
+
+func TestGetSettingsDefaultsMissingHostAndWarnsOnMissingDomain(t *testing.T) {
+	r := &reader{}
+	path := writeConfig(t, t.TempDir(), `{"settings":[
+		{"domain":"a.example.com","url_template":"https://example.com/{ip}"},
+		{"host":"@","url_template":"https://example.com/{ip}"}
+	]}`)
+
+	settings, warnings, err := r.GetSettings(path)
+	if err != nil {
+		t.Fatalf("GetSettings: %v", err)
+	}
+	if len(settings) != 1 {
+		t.Fatalf("got %d settings, want 1 (the entry with no domain should be skipped)", len(settings))
+	}
+	if settings[0].Host() != "@" {
+		t.Errorf("Host() = %q, want the default %q", settings[0].Host(), "@")
+	}
+	if len(warnings) != 1 {
+		t.Errorf("warnings = %v, want 1", warnings)
+	}
+}
+
+func TestGetSettingsMissingFileIsNotAnError(t *testing.T) {
+	r := &reader{}
+	settings, warnings, err := r.GetSettings(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	if err != nil {
+		t.Fatalf("GetSettings: %v", err)
+	}
+	if settings != nil || warnings != nil {
+		t.Errorf("settings = %v, warnings = %v, want both nil", settings, warnings)
+	}
+}
@@ -0,0 +1,100 @@
+// Package persistence stores each record's update history to disk as JSON
+// so it survives restarts.
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// Database persists and retrieves per-record update history.
+type Database interface {
+	GetEvents(domain, host string) ([]records.Event, error)
+	StoreEvent(domain, host string, event records.Event) error
+	RemoveRecord(domain, host string) error
+	Close() error
+}
+
+type key struct{ domain, host string }
+
+// jsonDatabase is a Database backed by a single JSON file, one array of
+// events per domain/host pair.
+type jsonDatabase struct {
+	mutex    sync.Mutex
+	filePath string
+	events   map[key][]records.Event
+}
+
+// NewJSON loads (or creates) the history file at dataDir/updates.json.
+func NewJSON(dataDir string) (Database, error) {
+	filePath := dataDir + "/updates.json"
+	db := &jsonDatabase{filePath: filePath, events: make(map[key][]records.Event)}
+
+	data, err := os.ReadFile(filePath)
+	switch {
+	case os.IsNotExist(err):
+		return db, nil
+	case err != nil:
+		return nil, fmt.Errorf("reading history file: %w", err)
+	}
+
+	var stored []storedEntry
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("parsing history file: %w", err)
+	}
+	for _, entry := range stored {
+		db.events[key{entry.Domain, entry.Host}] = entry.Events
+	}
+	return db, nil
+}
+
+type storedEntry struct {
+	Domain string          `json:"domain"`
+	Host   string          `json:"host"`
+	Events []records.Event `json:"events"`
+}
+
+func (db *jsonDatabase) GetEvents(domain, host string) ([]records.Event, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	return db.events[key{domain, host}], nil
+}
+
+func (db *jsonDatabase) StoreEvent(domain, host string, event records.Event) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	k := key{domain, host}
+	db.events[k] = append(db.events[k], event)
+	return db.writeLocked()
+}
+
+// RemoveRecord drops the stored history for a domain/host pair that is no
+// longer configured.
+func (db *jsonDatabase) RemoveRecord(domain, host string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	delete(db.events, key{domain, host})
+	return db.writeLocked()
+}
+
+func (db *jsonDatabase) Close() error { return nil }
+
+func (db *jsonDatabase) writeLocked() error {
+	stored := make([]storedEntry, 0, len(db.events))
+	for k, events := range db.events {
+		stored = append(stored, storedEntry{Domain: k.domain, Host: k.host, Events: events})
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("encoding history file: %w", err)
+	}
+	const filePermissions = 0o600
+	if err := os.WriteFile(db.filePath, data, filePermissions); err != nil {
+		return fmt.Errorf("writing history file: %w", err)
+	}
+	return nil
+}
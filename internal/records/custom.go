@@ -0,0 +1,46 @@
+package records
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/qdm12/golibs/network"
+)
+
+// Custom is a Settings implementation that pushes IP updates to a
+// user-provided URL template, substituting {domain}, {host} and {ip}.
+type Custom struct {
+	domain      string
+	host        string
+	urlTemplate string
+}
+
+// NewCustom builds a Custom Settings for domain/host, updated by GETing
+// urlTemplate with its placeholders substituted.
+func NewCustom(domain, host, urlTemplate string) *Custom {
+	return &Custom{domain: domain, host: host, urlTemplate: urlTemplate}
+}
+
+func (c *Custom) String() string   { return c.Host() + "." + c.Domain() }
+func (c *Custom) Domain() string   { return c.domain }
+func (c *Custom) Host() string     { return c.host }
+func (c *Custom) Provider() string { return "custom" }
+
+func (c *Custom) Update(ctx context.Context, client *network.Client, ip net.IP) error {
+	url := strings.NewReplacer(
+		"{domain}", c.domain,
+		"{host}", c.host,
+		"{ip}", ip.String(),
+	).Replace(c.urlTemplate)
+
+	content, status, err := client.Get(ctx, url)
+	if err != nil {
+		return fmt.Errorf("requesting update: %w", err)
+	}
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("%w: HTTP status %d: %s", ErrUpdateFailed, status, content)
+	}
+	return nil
+}
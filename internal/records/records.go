@@ -0,0 +1,74 @@
+// Package records defines a DNS record's configuration (Settings) and its
+// update history (Event), and ties the two together (Record).
+package records
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/qdm12/golibs/network"
+)
+
+// ErrUpdateFailed is wrapped by Settings.Update implementations when the
+// provider rejects the update.
+var ErrUpdateFailed = errors.New("update rejected by provider")
+
+// Settings is a single configured DNS record: where it lives and how to
+// push a new IP address to its provider.
+type Settings interface {
+	fmt.Stringer
+	Domain() string
+	Host() string
+	Provider() string
+	// Update pushes ip to the record's provider using client.
+	Update(ctx context.Context, client *network.Client, ip net.IP) error
+}
+
+// Event is a single historical outcome for a record: either a successful IP
+// change or a failed update attempt.
+type Event struct {
+	Time    time.Time
+	IP      net.IP
+	Success bool
+	Error   string
+}
+
+// Record tracks one Settings alongside its update history.
+type Record interface {
+	Settings
+	// Events returns the record's history, oldest first.
+	Events() []Event
+	// CurrentIP returns the IP of the most recent successful event, or nil
+	// if the record has never been updated successfully.
+	CurrentIP() net.IP
+	// AddEvent appends event to the record's history.
+	AddEvent(event Event)
+}
+
+type record struct {
+	Settings
+	events []Event
+}
+
+// New builds a Record for settings, seeded with its existing history.
+func New(settings Settings, events []Event) Record {
+	return &record{Settings: settings, events: events}
+}
+
+func (r *record) Events() []Event { return r.events }
+
+func (r *record) CurrentIP() net.IP {
+	for i := len(r.events) - 1; i >= 0; i-- {
+		if r.events[i].Success {
+			return r.events[i].IP
+		}
+	}
+	return nil
+}
+
+func (r *record) AddEvent(event Event) {
+	r.events = append(r.events, event)
+}
@@ -0,0 +1,172 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/data"
+	recordslib "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/update"
+)
+
+// Dependencies wires the RPC handlers to the rest of the running program.
+type Dependencies struct {
+	DB          *data.Database
+	GetEvents   func(domain, host string) ([]recordslib.Event, error)
+	ForceUpdate chan<- update.ForceUpdateRequest
+	GetIP       func(ctx context.Context, family string) (net.IP, error)
+	Reload      func() error
+	BackupRun   func() (filepath string, err error)
+}
+
+// forceUpdateTimeout bounds how long records.forceUpdate waits for the
+// runner to pick up the non-blocking force-update signal.
+const forceUpdateTimeout = 2 * time.Second
+
+// Handlers builds the method table for Server: records.list, records.get,
+// records.forceUpdate, records.history, ip.current, config.reload and
+// backup.run.
+func Handlers(deps Dependencies) map[string]Handler {
+	return map[string]Handler{
+		"records.list":        handleRecordsList(deps),
+		"records.get":         handleRecordsGet(deps),
+		"records.forceUpdate": handleRecordsForceUpdate(deps),
+		"records.history":     handleRecordsHistory(deps),
+		"ip.current":          handleIPCurrent(deps),
+		"config.reload":       handleConfigReload(deps),
+		"backup.run":          handleBackupRun(deps),
+	}
+}
+
+type recordSummary struct {
+	Domain string `json:"domain"`
+	Host   string `json:"host"`
+}
+
+func summarize(records []recordslib.Record) []recordSummary {
+	summaries := make([]recordSummary, len(records))
+	for i, record := range records {
+		summaries[i] = recordSummary{Domain: record.Domain(), Host: record.Host()}
+	}
+	return summaries
+}
+
+func findRecord(records []recordslib.Record, domain, host string) (recordslib.Record, bool) {
+	for _, record := range records {
+		if record.Domain() == domain && record.Host() == host {
+			return record, true
+		}
+	}
+	var zero recordslib.Record
+	return zero, false
+}
+
+func handleRecordsList(deps Dependencies) Handler {
+	return func(_ context.Context, _ json.RawMessage) (interface{}, *Error) {
+		return summarize(deps.DB.Records()), nil
+	}
+}
+
+type domainHostParams struct {
+	Domain string `json:"domain"`
+	Host   string `json:"host"`
+}
+
+func handleRecordsGet(deps Dependencies) Handler {
+	return func(_ context.Context, rawParams json.RawMessage) (interface{}, *Error) {
+		var p domainHostParams
+		if rpcErr := DecodeParams(rawParams, &p); rpcErr != nil {
+			return nil, rpcErr
+		}
+		record, ok := findRecord(deps.DB.Records(), p.Domain, p.Host)
+		if !ok {
+			return nil, InvalidParams("no such record: " + p.Domain + " " + p.Host)
+		}
+		return recordSummary{Domain: record.Domain(), Host: record.Host()}, nil
+	}
+}
+
+// handleRecordsForceUpdate triggers a forced check scoped to the requested
+// domain/host only; other configured records are left alone.
+func handleRecordsForceUpdate(deps Dependencies) Handler {
+	return func(ctx context.Context, rawParams json.RawMessage) (interface{}, *Error) {
+		var p domainHostParams
+		if rpcErr := DecodeParams(rawParams, &p); rpcErr != nil {
+			return nil, rpcErr
+		}
+		if _, ok := findRecord(deps.DB.Records(), p.Domain, p.Host); !ok {
+			return nil, InvalidParams("no such record: " + p.Domain + " " + p.Host)
+		}
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, forceUpdateTimeout)
+		defer cancel()
+		select {
+		case deps.ForceUpdate <- update.ForceUpdateRequest{Domain: p.Domain, Host: p.Host}:
+			return map[string]bool{"triggered": true}, nil
+		case <-timeoutCtx.Done():
+			return nil, InternalError(timeoutCtx.Err())
+		}
+	}
+}
+
+type historyParams struct {
+	Domain string `json:"domain"`
+	Host   string `json:"host"`
+	Limit  int    `json:"limit"`
+}
+
+func handleRecordsHistory(deps Dependencies) Handler {
+	return func(_ context.Context, rawParams json.RawMessage) (interface{}, *Error) {
+		var p historyParams
+		if rpcErr := DecodeParams(rawParams, &p); rpcErr != nil {
+			return nil, rpcErr
+		}
+		events, err := deps.GetEvents(p.Domain, p.Host)
+		if err != nil {
+			return nil, InternalError(err)
+		}
+		if p.Limit > 0 && p.Limit < len(events) {
+			events = events[len(events)-p.Limit:]
+		}
+		return events, nil
+	}
+}
+
+type ipCurrentParams struct {
+	Family string `json:"family"`
+}
+
+func handleIPCurrent(deps Dependencies) Handler {
+	return func(ctx context.Context, rawParams json.RawMessage) (interface{}, *Error) {
+		var p ipCurrentParams
+		if rpcErr := DecodeParams(rawParams, &p); rpcErr != nil {
+			return nil, rpcErr
+		}
+		ip, err := deps.GetIP(ctx, p.Family)
+		if err != nil {
+			return nil, InternalError(err)
+		}
+		return map[string]string{"ip": ip.String()}, nil
+	}
+}
+
+func handleConfigReload(deps Dependencies) Handler {
+	return func(_ context.Context, _ json.RawMessage) (interface{}, *Error) {
+		if err := deps.Reload(); err != nil {
+			return nil, InternalError(err)
+		}
+		return map[string]bool{"reloaded": true}, nil
+	}
+}
+
+func handleBackupRun(deps Dependencies) Handler {
+	return func(_ context.Context, _ json.RawMessage) (interface{}, *Error) {
+		filepath, err := deps.BackupRun()
+		if err != nil {
+			return nil, InternalError(err)
+		}
+		return map[string]string{"filepath": filepath}, nil
+	}
+}
@@ -0,0 +1,15 @@
+package rpc
+
+import "encoding/json"
+
+// DecodeParams unmarshals rawParams into dst, returning an InvalidParams
+// error instead of a parse error so callers get a JSON-RPC-shaped response.
+func DecodeParams(rawParams json.RawMessage, dst interface{}) *Error {
+	if len(rawParams) == 0 {
+		return InvalidParams("missing params")
+	}
+	if err := json.Unmarshal(rawParams, dst); err != nil {
+		return InvalidParams(err.Error())
+	}
+	return nil
+}
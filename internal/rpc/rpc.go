@@ -0,0 +1,61 @@
+// Package rpc implements a JSON-RPC 2.0 control API (POST /rpc) so DDNS
+// records, history and forced updates can be driven from scripts/CI instead
+// of scraping the HTML UI.
+package rpc
+
+import "encoding/json"
+
+const Version = "2.0"
+
+// Standard JSON-RPC 2.0 error codes.
+const (
+	ParseErrorCode     = -32700
+	InvalidRequestCode = -32600
+	MethodNotFoundCode = -32601
+	InvalidParamsCode  = -32602
+	InternalErrorCode  = -32603
+)
+
+// Request is a single JSON-RPC 2.0 call. A Request with no ID is a
+// notification: it is dispatched but never gets a Response.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+func (r Request) isNotification() bool { return len(r.ID) == 0 }
+
+// Response is a single JSON-RPC 2.0 reply.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC 2.0 error object.
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func newError(code int, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// InvalidParams builds an InvalidParamsCode error, for handlers rejecting
+// params that parsed fine but fail validation (e.g. an unknown domain/host).
+func InvalidParams(message string) *Error {
+	return newError(InvalidParamsCode, message)
+}
+
+// InternalError builds an InternalErrorCode error from an underlying Go
+// error, e.g. a failed database read.
+func InternalError(err error) *Error {
+	return newError(InternalErrorCode, err.Error())
+}
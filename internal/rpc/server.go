@@ -0,0 +1,163 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/qdm12/ddns-updater/internal/logging"
+)
+
+// Handler serves a single JSON-RPC method. rawParams is the raw "params"
+// value from the request and may be nil.
+type Handler func(ctx context.Context, rawParams json.RawMessage) (result interface{}, rpcErr *Error)
+
+// Server serves the JSON-RPC 2.0 control API.
+type Server struct {
+	address  string
+	token    string
+	logger   logging.Logger
+	handlers map[string]Handler
+}
+
+// New creates an RPC server. If token is empty, requests are not
+// authenticated; otherwise callers must send `Authorization: Bearer <token>`.
+func New(address, token string, logger logging.Logger, handlers map[string]Handler) *Server {
+	return &Server{address: address, token: token, logger: logger, handlers: handlers}
+}
+
+// Run serves the RPC API until ctx is cancelled.
+func (s *Server) Run(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rpc", s.handleRPC)
+	httpServer := &http.Server{Addr: s.address, Handler: s.authenticate(mux)}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpServer.ListenAndServe() }()
+
+	select {
+	case <-ctx.Done():
+		return httpServer.Shutdown(context.Background())
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+s.token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleRPC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		writeResponses(w, []Response{{JSONRPC: Version, Error: newError(ParseErrorCode, err.Error())}})
+		return
+	}
+
+	requests, batch, parseErr := decodeRequests(raw)
+	if parseErr != nil {
+		writeResponses(w, []Response{{JSONRPC: Version, Error: parseErr}})
+		return
+	}
+
+	responses := make([]Response, 0, len(requests))
+	for _, req := range requests {
+		if resp, ok := s.dispatch(r.Context(), req); ok {
+			responses = append(responses, resp)
+		}
+	}
+
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if batch {
+		writeResponses(w, responses)
+		return
+	}
+	writeResponses(w, responses[:1])
+}
+
+// decodeRequests accepts either a single JSON-RPC request object or a batch
+// (JSON array) of them. An empty batch is itself invalid per the spec.
+func decodeRequests(raw json.RawMessage) (requests []Request, batch bool, rpcErr *Error) {
+	trimmed := trimLeadingSpace(raw)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(raw, &requests); err != nil {
+			return nil, false, newError(InvalidRequestCode, err.Error())
+		}
+		if len(requests) == 0 {
+			return nil, true, newError(InvalidRequestCode, "empty batch")
+		}
+		return requests, true, nil
+	}
+
+	var req Request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, false, newError(InvalidRequestCode, err.Error())
+	}
+	return []Request{req}, false, nil
+}
+
+func trimLeadingSpace(raw json.RawMessage) json.RawMessage {
+	i := 0
+	for i < len(raw) && (raw[i] == ' ' || raw[i] == '\t' || raw[i] == '\n' || raw[i] == '\r') {
+		i++
+	}
+	return raw[i:]
+}
+
+// dispatch runs req against the registered handler and reports whether a
+// Response should be written: notifications (no ID) never get one.
+func (s *Server) dispatch(ctx context.Context, req Request) (resp Response, shouldRespond bool) {
+	respond := !req.isNotification()
+
+	if req.JSONRPC != Version {
+		return errorResponse(req.ID, newError(InvalidRequestCode, "jsonrpc must be \"2.0\"")), respond
+	}
+
+	handler, ok := s.handlers[req.Method]
+	if !ok {
+		return errorResponse(req.ID, newError(MethodNotFoundCode, "unknown method: "+req.Method)), respond
+	}
+
+	result, rpcErr := handler(ctx, req.Params)
+	if rpcErr != nil {
+		if respond {
+			s.logger.Warn("rpc method %s failed: %s", req.Method, rpcErr.Message)
+		}
+		return errorResponse(req.ID, rpcErr), respond
+	}
+	return Response{JSONRPC: Version, ID: req.ID, Result: result}, respond
+}
+
+func errorResponse(id json.RawMessage, rpcErr *Error) Response {
+	return Response{JSONRPC: Version, ID: id, Error: rpcErr}
+}
+
+func writeResponses(w http.ResponseWriter, responses []Response) {
+	w.Header().Set("Content-Type", "application/json")
+	var payload interface{} = responses
+	if len(responses) == 1 {
+		payload = responses[0]
+	}
+	_ = json.NewEncoder(w).Encode(payload)
+}
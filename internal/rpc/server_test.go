@@ -0,0 +1,56 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeRequestsSingle(t *testing.T) {
+	requests, batch, rpcErr := decodeRequests(json.RawMessage(`{"jsonrpc":"2.0","method":"records.list","id":1}`))
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	if batch {
+		t.Error("batch = true, want false for a single object")
+	}
+	if len(requests) != 1 || requests[0].Method != "records.list" {
+		t.Errorf("requests = %+v", requests)
+	}
+}
+
+func TestDecodeRequestsBatch(t *testing.T) {
+	requests, batch, rpcErr := decodeRequests(json.RawMessage(
+		`[{"jsonrpc":"2.0","method":"records.list","id":1},{"jsonrpc":"2.0","method":"ip.current","id":2}]`))
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	if !batch {
+		t.Error("batch = false, want true for a JSON array")
+	}
+	if len(requests) != 2 {
+		t.Errorf("requests = %+v, want 2 entries", requests)
+	}
+}
+
+func TestDecodeRequestsEmptyBatchIsInvalid(t *testing.T) {
+	_, batch, rpcErr := decodeRequests(json.RawMessage(`[]`))
+	if rpcErr == nil {
+		t.Fatal("expected an error for an empty batch")
+	}
+	if rpcErr.Code != InvalidRequestCode {
+		t.Errorf("code = %d, want %d", rpcErr.Code, InvalidRequestCode)
+	}
+	if !batch {
+		t.Error("batch = false, want true: [] is still an array")
+	}
+}
+
+func TestDecodeRequestsMalformed(t *testing.T) {
+	_, _, rpcErr := decodeRequests(json.RawMessage(`{not json`))
+	if rpcErr == nil {
+		t.Fatal("expected an error for malformed JSON")
+	}
+	if rpcErr.Code != InvalidRequestCode {
+		t.Errorf("code = %d, want %d", rpcErr.Code, InvalidRequestCode)
+	}
+}
@@ -0,0 +1,66 @@
+// Package server serves the web UI and its backing HTTP API for viewing and
+// force-updating records.
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/qdm12/ddns-updater/internal/data"
+	"github.com/qdm12/ddns-updater/internal/logging"
+	"github.com/qdm12/ddns-updater/internal/update"
+)
+
+// Server serves the web UI.
+type Server struct {
+	address     string
+	rootURL     string
+	uiDir       string
+	db          *data.Database
+	logger      logging.Logger
+	forceUpdate chan<- update.ForceUpdateRequest
+}
+
+// New builds a Server serving the UI under uiDir, rooted at rootURL.
+// Requests to trigger a force update push to forceUpdate.
+func New(address, rootURL, uiDir string, db *data.Database, logger logging.Logger,
+	forceUpdate chan<- update.ForceUpdateRequest) *Server {
+	return &Server{
+		address: address, rootURL: rootURL, uiDir: uiDir,
+		db: db, logger: logger, forceUpdate: forceUpdate,
+	}
+}
+
+// Run serves the UI until ctx is cancelled.
+func (s *Server) Run(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(s.rootURL, http.StripPrefix(s.rootURL, http.FileServer(http.Dir(s.uiDir))))
+	mux.HandleFunc(s.rootURL+"update", s.handleUpdate)
+	httpServer := &http.Server{Addr: s.address, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpServer.ListenAndServe() }()
+
+	select {
+	case <-ctx.Done():
+		return httpServer.Shutdown(context.Background())
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	select {
+	case s.forceUpdate <- update.ForceUpdateRequest{}:
+	default:
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,9 @@
+// Package splash renders the startup banner printed once at launch.
+package splash
+
+import "fmt"
+
+// Splash renders the startup banner for the given build metadata.
+func Splash(version, vcsRef, buildDate string) string {
+	return fmt.Sprintf("DDNS Updater %s (commit %s, built %s)", version, vcsRef, buildDate)
+}
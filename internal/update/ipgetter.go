@@ -0,0 +1,84 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/qdm12/ddns-updater/internal/metrics"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/golibs/network"
+)
+
+// defaultIPURLs maps the built-in IPMethods to the lookup URL they query.
+var defaultIPURLs = map[models.IPMethod]string{
+	models.IPMethodProvider: "https://ipinfo.io/ip",
+	models.IPMethodGoogle:   "https://domains.google.com/checkip",
+	models.IPMethodOpenDNS:  "https://diagnostic.opendns.com/myip",
+}
+
+// IPGetter looks up the caller's public IP address, per address family.
+type IPGetter struct {
+	client     *network.Client
+	ipMethod   models.IPMethod
+	ipv4Method models.IPMethod
+	ipv6Method models.IPMethod
+	recorder   metrics.Recorder
+}
+
+// NewIPGetter builds an IPGetter. ipMethod is used when a family-specific
+// method is not set. recorder is sent a count of lookup failures, by
+// method, for Prometheus.
+func NewIPGetter(client *network.Client, ipMethod, ipv4Method, ipv6Method models.IPMethod,
+	recorder metrics.Recorder) *IPGetter {
+	return &IPGetter{
+		client: client, ipMethod: ipMethod, ipv4Method: ipv4Method, ipv6Method: ipv6Method,
+		recorder: recorder,
+	}
+}
+
+// IP returns the public IP address for family ("ipv4" or "ipv6"); an empty
+// family uses the general ipMethod.
+func (g *IPGetter) IP(ctx context.Context, family string) (net.IP, error) {
+	method := g.methodFor(family)
+	url, ok := lookupURL(method)
+	if !ok {
+		url = string(method)
+	}
+
+	content, status, err := g.client.Get(ctx, url)
+	if err != nil {
+		g.recorder.RecordIPLookupFailure(string(method))
+		return nil, fmt.Errorf("looking up public IP: %w", err)
+	}
+	if status < 200 || status >= 300 {
+		g.recorder.RecordIPLookupFailure(string(method))
+		return nil, fmt.Errorf("%w: HTTP status %d", errIPLookupFailed, status)
+	}
+
+	ip := net.ParseIP(string(content))
+	if ip == nil {
+		g.recorder.RecordIPLookupFailure(string(method))
+		return nil, fmt.Errorf("%w: invalid IP address in response: %q", errIPLookupFailed, content)
+	}
+	return ip, nil
+}
+
+func (g *IPGetter) methodFor(family string) models.IPMethod {
+	switch family {
+	case "ipv4":
+		if g.ipv4Method != "" {
+			return g.ipv4Method
+		}
+	case "ipv6":
+		if g.ipv6Method != "" {
+			return g.ipv6Method
+		}
+	}
+	return g.ipMethod
+}
+
+func lookupURL(method models.IPMethod) (string, bool) {
+	url, ok := defaultIPURLs[method]
+	return url, ok
+}
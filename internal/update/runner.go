@@ -0,0 +1,97 @@
+package update
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/data"
+	"github.com/qdm12/ddns-updater/internal/logging"
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// ForceUpdateRequest asks Runner to check records out of turn, outside its
+// usual period. A zero value targets every configured record; a non-empty
+// Domain scopes the check to that single domain/host pair.
+type ForceUpdateRequest struct {
+	Domain string
+	Host   string
+}
+
+// Runner periodically checks the public IP and updates every configured
+// record whose provider is out of date.
+type Runner struct {
+	db       *data.Database
+	updater  *Updater
+	ipGetter *IPGetter
+	logger   logging.Logger
+	timeNow  func() time.Time
+
+	mutex  sync.Mutex
+	period time.Duration
+}
+
+// NewRunner builds a Runner.
+func NewRunner(db *data.Database, updater *Updater, ipGetter *IPGetter,
+	logger logging.Logger, timeNow func() time.Time) *Runner {
+	return &Runner{db: db, updater: updater, ipGetter: ipGetter, logger: logger, timeNow: timeNow}
+}
+
+// Run checks every period, and whenever a request arrives on forceUpdate,
+// whether the current public IP matches each targeted record and updates
+// the ones that don't, until ctx is cancelled.
+func (r *Runner) Run(ctx context.Context, period time.Duration, forceUpdate <-chan ForceUpdateRequest) error {
+	r.mutex.Lock()
+	r.period = period
+	r.mutex.Unlock()
+
+	timer := time.NewTimer(period)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case req := <-forceUpdate:
+			r.runOnce(ctx, req)
+		case <-timer.C:
+			r.runOnce(ctx, ForceUpdateRequest{})
+			timer.Reset(r.currentPeriod())
+		}
+	}
+}
+
+// ReloadConfig swaps in a new update period, taking effect on the next tick.
+// newRecords itself needs no handling here: the caller already swaps it
+// into db via db.SetRecords, which runOnce reads from directly.
+func (r *Runner) ReloadConfig(period time.Duration, newRecords []records.Record) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.period = period
+}
+
+func (r *Runner) currentPeriod() time.Duration {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.period
+}
+
+func (r *Runner) runOnce(ctx context.Context, req ForceUpdateRequest) {
+	for _, record := range r.db.Records() {
+		if req.Domain != "" && (record.Domain() != req.Domain || record.Host() != req.Host) {
+			continue
+		}
+
+		const family = "ipv4"
+		ip, err := r.ipGetter.IP(ctx, family)
+		if err != nil {
+			r.logger.Error(err)
+			continue
+		}
+		if currentIP := record.CurrentIP(); currentIP != nil && currentIP.Equal(ip) {
+			continue
+		}
+		if err := r.updater.Update(ctx, record, ip); err != nil {
+			r.logger.Error(err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package update drives the record-update loop: fetching the current
+// public IP and pushing it to each configured record's provider.
+package update
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/data"
+	"github.com/qdm12/ddns-updater/internal/metrics"
+	"github.com/qdm12/ddns-updater/internal/notify"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/golibs/network"
+)
+
+var errIPLookupFailed = errors.New("IP lookup failed")
+
+// Updater pushes a new IP address to a single record's provider and records
+// the outcome.
+type Updater struct {
+	db       *data.Database
+	client   *network.Client
+	notifier notify.Notifier
+	recorder metrics.Recorder
+	timeNow  func() time.Time
+}
+
+// NewUpdater builds an Updater. notifier is sent structured update outcomes.
+// recorder is sent update attempts, durations and successes for Prometheus.
+func NewUpdater(db *data.Database, client *network.Client, notifier notify.Notifier,
+	recorder metrics.Recorder, timeNow func() time.Time) *Updater {
+	return &Updater{db: db, client: client, notifier: notifier, recorder: recorder, timeNow: timeNow}
+}
+
+// Update pushes newIP to record's provider and stores the outcome as a
+// history event.
+func (u *Updater) Update(ctx context.Context, record records.Record, newIP net.IP) error {
+	oldIP := record.CurrentIP()
+	fields := []notify.Field{
+		notify.F("domain", record.Domain()),
+		notify.F("host", record.Host()),
+		notify.F("provider", record.Provider()),
+		notify.F("old_ip", ipString(oldIP)),
+		notify.F("new_ip", newIP.String()),
+	}
+
+	start := u.timeNow()
+	err := record.Update(ctx, u.client, newIP)
+	now := u.timeNow()
+	u.recorder.ObserveUpdateDuration(record.Provider(), record.Domain(), record.Host(), now.Sub(start))
+
+	event := records.Event{Time: now, IP: newIP, Success: err == nil}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	if storeErr := u.db.StoreEvent(record.Domain(), record.Host(), event); storeErr != nil {
+		return storeErr
+	}
+
+	if err != nil {
+		u.recorder.RecordUpdateAttempt(record.Provider(), record.Domain(), record.Host(), "failure")
+		fields = append(fields, notify.F("error", err.Error()))
+		_ = u.notifier.Notify(ctx, notify.LevelError, "update failed for "+record.String(), fields...)
+		return err
+	}
+
+	u.recorder.RecordUpdateAttempt(record.Provider(), record.Domain(), record.Host(), "success")
+	u.recorder.SetCurrentIP(record.Domain(), record.Host(), family(newIP), newIP)
+	u.recorder.SetLastSuccess(record.Domain(), record.Host(), event.Time)
+	_ = u.notifier.Notify(ctx, notify.LevelInfo, record.String()+" updated", fields...)
+	return nil
+}
+
+func ipString(ip net.IP) string {
+	if ip == nil {
+		return "unknown"
+	}
+	return ip.String()
+}
+
+func family(ip net.IP) string {
+	if ip.To4() != nil {
+		return "ipv4"
+	}
+	return "ipv6"
+}